@@ -0,0 +1,67 @@
+package main
+
+import (
+	"context"
+	"net"
+
+	"github.com/rs/zerolog"
+	"google.golang.org/grpc"
+	_ "google.golang.org/grpc/encoding/gzip" // registers gzip compressor negotiation
+
+	coltracepb "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+
+	"github.com/artbyoscar/agenttrace/pkg/grpcx"
+	"github.com/artbyoscar/agenttrace/pkg/httpx"
+	"github.com/artbyoscar/agenttrace/pkg/otlp"
+)
+
+// otlpGRPCServer implements the OTLP gRPC TraceService using the same
+// span normalization and ingestion pipeline as the HTTP receiver.
+type otlpGRPCServer struct {
+	coltracepb.UnimplementedTraceServiceServer
+}
+
+func (s *otlpGRPCServer) Export(ctx context.Context, req *coltracepb.ExportTraceServiceRequest) (*coltracepb.ExportTraceServiceResponse, error) {
+	spans, rejected := otlp.ToSpans(req.GetResourceSpans())
+	if err := ingestSpans(ctx, "otlp-grpc", spans); err != nil {
+		return nil, grpcIngestError(err)
+	}
+
+	resp := &coltracepb.ExportTraceServiceResponse{}
+	if rejected > 0 {
+		resp.PartialSuccess = &coltracepb.ExportTracePartialSuccess{
+			RejectedSpans: rejected,
+			ErrorMessage:  "some spans had malformed trace_id/span_id and were dropped",
+		}
+	}
+	return resp, nil
+}
+
+// newGRPCServer builds the gRPC server hosting the OTLP TraceService,
+// with interceptors mirroring the HTTP middleware stack.
+func newGRPCServer(logger zerolog.Logger, authOpts httpx.AuthOptions) *grpc.Server {
+	server := grpc.NewServer(
+		// LoggingInterceptor must be listed before RecoveryInterceptor:
+		// grpc-go's ChainUnaryInterceptor makes the first entry
+		// outermost, and Logging's post-handler log line has to run
+		// even when the handler panics, so Recovery (which stops the
+		// panic) needs to be innermost, not outside it.
+		grpc.ChainUnaryInterceptor(
+			grpcx.LoggingInterceptor(logger),
+			grpcx.RecoveryInterceptor(logger),
+			grpcx.AuthInterceptor(authOpts.Tokens, len(authOpts.HMACSecret) > 0),
+		),
+	)
+	coltracepb.RegisterTraceServiceServer(server, &otlpGRPCServer{})
+	return server
+}
+
+// serveGRPC listens on addr and serves until the listener is closed
+// (e.g. by server.GracefulStop from the shutdown path in main).
+func serveGRPC(server *grpc.Server, addr string) error {
+	lis, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+	return server.Serve(lis)
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/artbyoscar/agenttrace/pkg/ingest"
+	"github.com/artbyoscar/agenttrace/pkg/trace"
+)
+
+var errBodyTooLarge = errors.New("request body exceeds max body size")
+
+// pipeline is the process-wide ingestion pipeline; it's set once in
+// main and shared by every format's handler.
+var pipeline *ingest.Pipeline
+
+// ingestSpans hands normalized spans to the pipeline for async
+// delivery to the configured sinks, recording which format they came
+// from for the per-format counters.
+func ingestSpans(_ context.Context, format string, spans []trace.Span) error {
+	if err := pipeline.Submit(spans); err != nil {
+		return err
+	}
+	ingestSpansTotal.WithLabelValues(format).Add(float64(len(spans)))
+	return nil
+}
+
+// retryAfterSeconds is advertised to clients that get shed under
+// backpressure; it's a fixed estimate of how long a full queue takes to
+// drain one worker cycle, not a promise.
+const retryAfterSeconds = "1"
+
+// writeIngestError maps a pipeline/ingest error onto the right HTTP
+// status, including 429 + Retry-After for backpressure.
+func writeIngestError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, ingest.ErrQueueFull):
+		w.Header().Set("Retry-After", retryAfterSeconds)
+		http.Error(w, "ingestion queue is full, try again shortly", http.StatusTooManyRequests)
+	case errors.Is(err, ingest.ErrShuttingDown):
+		http.Error(w, "ingestion service is shutting down", http.StatusServiceUnavailable)
+	default:
+		http.Error(w, "failed to accept spans", http.StatusServiceUnavailable)
+	}
+}
+
+// grpcIngestError mirrors writeIngestError for the gRPC receiver:
+// backpressure and shutdown both surface as codes.Unavailable so OTLP
+// gRPC exporters retry per their standard backoff policy.
+func grpcIngestError(err error) error {
+	switch {
+	case errors.Is(err, ingest.ErrQueueFull), errors.Is(err, ingest.ErrShuttingDown):
+		return status.Error(codes.Unavailable, err.Error())
+	default:
+		return status.Error(codes.Internal, "failed to accept spans")
+	}
+}
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"io"
+	"net/http"
+
+	"github.com/artbyoscar/agenttrace/pkg/ingest"
+	"github.com/artbyoscar/agenttrace/pkg/ingest/translate"
+)
+
+// jaegerHandler accepts Jaeger's Thrift-over-HTTP batch format, as
+// emitted by jaeger-agent and older jaeger-client-go HTTP reporters.
+type jaegerHandler struct {
+	maxBodyBytes int64
+}
+
+func newJaegerHandler(maxBodyBytes int64) *jaegerHandler {
+	if maxBodyBytes <= 0 {
+		maxBodyBytes = defaultMaxBodyBytes
+	}
+	return &jaegerHandler{maxBodyBytes: maxBodyBytes}
+}
+
+func (h *jaegerHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if pipeline.Utilization() >= ingest.HighWatermark {
+		writeIngestError(w, ingest.ErrQueueFull)
+		return
+	}
+
+	body, err := io.ReadAll(io.LimitReader(r.Body, h.maxBodyBytes+1))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if int64(len(body)) > h.maxBodyBytes {
+		http.Error(w, errBodyTooLarge.Error(), http.StatusBadRequest)
+		return
+	}
+
+	spans, err := translate.FromJaegerThrift(body)
+	if err != nil {
+		http.Error(w, "malformed jaeger batch: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := ingestSpans(r.Context(), "jaeger", spans); err != nil {
+		writeIngestError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusAccepted)
+}
@@ -1,42 +1,161 @@
 package main
 
 import (
+	"context"
+	"flag"
 	"log"
 	"net/http"
 	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
+	"time"
 
 	"github.com/gorilla/mux"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/rs/zerolog"
+	"golang.org/x/sync/errgroup"
+	"google.golang.org/grpc"
+
+	"github.com/artbyoscar/agenttrace/pkg/httpx"
+	"github.com/artbyoscar/agenttrace/pkg/ingest"
+	"github.com/artbyoscar/agenttrace/pkg/sink"
 )
 
+// drainTimeout bounds how long shutdown waits for the queue to empty
+// before giving up and exiting anyway.
+const drainTimeout = 30 * time.Second
+
+// defaultOTLPGRPCPort matches the OTel convention for the OTLP gRPC
+// receiver, distinct from the HTTP receiver's port.
+const defaultOTLPGRPCPort = "4317"
+
 func main() {
+	grpcDisable := flag.Bool("otlp-grpc-disable", false, "disable the OTLP gRPC receiver and serve HTTP only")
+	flag.Parse()
+
 	port := os.Getenv("INGESTION_PORT")
 	if port == "" {
 		port = "8001"
 	}
+	grpcPort := os.Getenv("OTLP_GRPC_PORT")
+	if grpcPort == "" {
+		grpcPort = defaultOTLPGRPCPort
+	}
+
+	maxBodyBytes := int64(defaultMaxBodyBytes)
+	if v := os.Getenv("AGENTTRACE_MAX_BODY_BYTES"); v != "" {
+		n, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			log.Fatalf("invalid AGENTTRACE_MAX_BODY_BYTES %q: %v", v, err)
+		}
+		maxBodyBytes = n
+	}
+
+	logger := zerolog.New(os.Stdout).With().Timestamp().Logger()
+	authOpts := httpx.AuthOptionsFromEnv()
+	authOpts.MaxBodyBytes = maxBodyBytes
+
+	sinks, err := sink.SinksFromEnv()
+	if err != nil {
+		log.Fatalf("invalid AGENTTRACE_SINKS: %v", err)
+	}
+	pipeline = ingest.NewPipeline(sinks, 1024, 8)
 
 	router := mux.NewRouter()
+	// Logging must be outermost: gorilla/mux makes the first Use call
+	// the outermost wrapper, and Logging's post-handler log line has to
+	// run even when the handler panics, so Recovery (which stops the
+	// panic) needs to sit inside it, not outside.
+	router.Use(httpx.Logging(logger))
+	router.Use(httpx.Recovery(logger))
 
-	// Health check endpoint
+	// Health check and metrics stay unauthenticated and CORS-agnostic:
+	// k8s liveness probes and Prometheus scrapers don't send a bearer
+	// token or signed body, so they're registered directly on router
+	// rather than the ingestRouter subrouter below.
 	router.HandleFunc("/health", healthCheck).Methods("GET")
+	router.Handle("/metrics", promhttp.Handler()).Methods("GET")
+
+	// CORS and auth apply only to the span-ingestion endpoints.
+	ingestRouter := router.PathPrefix("/").Subrouter()
+	ingestRouter.Use(httpx.CORS(httpx.CORSOptionsFromEnv()))
+	ingestRouter.Use(httpx.Auth(authOpts))
+
+	// OTLP/HTTP trace export. /ingest is kept as an alias for existing
+	// deployments that predate the OTLP receiver.
+	otlp := newOTLPHandler(maxBodyBytes)
+	// OPTIONS is matched too so browser preflights reach the CORS
+	// middleware, which answers them before Auth or the handler runs.
+	ingestRouter.Handle("/v1/traces", otlp).Methods("POST", "OPTIONS")
+	ingestRouter.Handle("/ingest", otlp).Methods("POST", "OPTIONS")
 
-	// Ingestion endpoint
-	router.HandleFunc("/ingest", ingestTraces).Methods("POST")
+	// Jaeger Thrift-over-HTTP and Zipkin v2 JSON, for existing
+	// deployments that can't switch their SDK/agent to OTLP outright.
+	ingestRouter.Handle("/api/traces", newJaegerHandler(maxBodyBytes)).Methods("POST", "OPTIONS")
+	ingestRouter.Handle("/api/v2/spans", newZipkinHandler(maxBodyBytes)).Methods("POST", "OPTIONS")
+
+	httpServer := &http.Server{Addr: ":" + port, Handler: router}
+
+	var grpcServer *grpc.Server
+	if !*grpcDisable {
+		grpcServer = newGRPCServer(logger, authOpts)
+	}
 
-	log.Printf("Ingestion service starting on port %s", port)
-	if err := http.ListenAndServe(":"+port, router); err != nil {
+	group, ctx := errgroup.WithContext(context.Background())
+	group.Go(func() error {
+		logger.Info().Str("port", port).Msg("ingestion HTTP server starting")
+		if err := httpServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			return err
+		}
+		return nil
+	})
+	if grpcServer != nil {
+		group.Go(func() error {
+			logger.Info().Str("port", grpcPort).Msg("ingestion OTLP gRPC server starting")
+			return serveGRPC(grpcServer, ":"+grpcPort)
+		})
+	}
+	group.Go(func() error {
+		return awaitShutdown(ctx, httpServer, grpcServer, logger)
+	})
+
+	if err := group.Wait(); err != nil {
 		log.Fatal(err)
 	}
 }
 
+// awaitShutdown blocks until SIGTERM/SIGINT (or the group context is
+// cancelled by another server's failure), then stops accepting new
+// requests on both servers, drains the ingestion queue, and returns.
+func awaitShutdown(ctx context.Context, httpServer *http.Server, grpcServer *grpc.Server, logger zerolog.Logger) error {
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, syscall.SIGTERM, syscall.SIGINT)
+
+	select {
+	case <-sigCh:
+	case <-ctx.Done():
+	}
+
+	logger.Info().Msg("shutdown signal received, draining ingestion queue")
+	drainCtx, cancel := context.WithTimeout(context.Background(), drainTimeout)
+	defer cancel()
+
+	if err := httpServer.Shutdown(drainCtx); err != nil {
+		logger.Error().Err(err).Msg("error stopping http server")
+	}
+	if grpcServer != nil {
+		grpcServer.GracefulStop()
+	}
+	if err := pipeline.Shutdown(drainCtx); err != nil {
+		logger.Error().Err(err).Msg("ingestion queue did not drain before timeout")
+	}
+	logger.Info().Int64("dropped_spans", pipeline.Dropped()).Msg("ingestion service stopped")
+	return nil
+}
+
 func healthCheck(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
 	w.Write([]byte(`{"status":"healthy"}`))
 }
-
-func ingestTraces(w http.ResponseWriter, r *http.Request) {
-	// TODO: Implement trace ingestion
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusAccepted)
-	w.Write([]byte(`{"message":"Trace accepted for processing"}`))
-}
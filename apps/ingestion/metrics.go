@@ -0,0 +1,26 @@
+package main
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// ingestSpansTotal counts accepted spans by source format, so operators
+// can tell which ingestion paths are actually in use.
+var ingestSpansTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "agenttrace_ingest_spans_total",
+	Help: "Total number of spans accepted by the ingestion service, by source format.",
+}, []string{"format"})
+
+// ingestSpansDroppedTotal exposes the pipeline's cumulative dropped-span
+// count (queue full at submit time) so it's visible to the same
+// scraper as ingestSpansTotal, rather than only living in memory.
+var ingestSpansDroppedTotal = promauto.NewCounterFunc(prometheus.CounterOpts{
+	Name: "agenttrace_ingest_spans_dropped_total",
+	Help: "Total number of spans dropped because the ingestion queue was full.",
+}, func() float64 {
+	if pipeline == nil {
+		return 0
+	}
+	return float64(pipeline.Dropped())
+})
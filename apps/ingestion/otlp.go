@@ -0,0 +1,121 @@
+package main
+
+import (
+	"compress/gzip"
+	"io"
+	"net/http"
+
+	coltracepb "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/artbyoscar/agenttrace/pkg/ingest"
+	"github.com/artbyoscar/agenttrace/pkg/otlp"
+)
+
+// defaultMaxBodyBytes bounds the size of a single export request. It can
+// be overridden with AGENTTRACE_MAX_BODY_BYTES.
+const defaultMaxBodyBytes = 16 << 20 // 16 MiB
+
+// otlpHandler implements the OTLP/HTTP trace export endpoint described in
+// https://opentelemetry.io/docs/specs/otlp/#otlphttp.
+type otlpHandler struct {
+	maxBodyBytes int64
+}
+
+func newOTLPHandler(maxBodyBytes int64) *otlpHandler {
+	if maxBodyBytes <= 0 {
+		maxBodyBytes = defaultMaxBodyBytes
+	}
+	return &otlpHandler{maxBodyBytes: maxBodyBytes}
+}
+
+func (h *otlpHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if pipeline.Utilization() >= ingest.HighWatermark {
+		writeIngestError(w, ingest.ErrQueueFull)
+		return
+	}
+
+	body, err := h.readBody(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	req := &coltracepb.ExportTraceServiceRequest{}
+	contentType := r.Header.Get("Content-Type")
+	switch {
+	case contentType == "application/x-protobuf" || contentType == "":
+		err = proto.Unmarshal(body, req)
+	case contentType == "application/json":
+		err = protojson.Unmarshal(body, req)
+	default:
+		http.Error(w, "unsupported content-type: "+contentType, http.StatusUnsupportedMediaType)
+		return
+	}
+	if err != nil {
+		http.Error(w, "malformed export request: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	spans, rejected := otlp.ToSpans(req.GetResourceSpans())
+	if err := ingestSpans(r.Context(), "otlp", spans); err != nil {
+		writeIngestError(w, err)
+		return
+	}
+
+	resp := &coltracepb.ExportTraceServiceResponse{}
+	if rejected > 0 {
+		resp.PartialSuccess = &coltracepb.ExportTracePartialSuccess{
+			RejectedSpans: rejected,
+			ErrorMessage:  "some spans had malformed trace_id/span_id and were dropped",
+		}
+	}
+
+	h.writeResponse(w, r, resp)
+}
+
+func (h *otlpHandler) readBody(r *http.Request) ([]byte, error) {
+	reader := io.Reader(r.Body)
+	if r.Header.Get("Content-Encoding") == "gzip" {
+		gz, err := gzip.NewReader(r.Body)
+		if err != nil {
+			return nil, err
+		}
+		defer gz.Close()
+		reader = gz
+	}
+	limited := io.LimitReader(reader, h.maxBodyBytes+1)
+	body, err := io.ReadAll(limited)
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(body)) > h.maxBodyBytes {
+		return nil, errBodyTooLarge
+	}
+	return body, nil
+}
+
+func (h *otlpHandler) writeResponse(w http.ResponseWriter, r *http.Request, resp *coltracepb.ExportTraceServiceResponse) {
+	accept := r.Header.Get("Content-Type")
+	if accept == "application/json" {
+		body, err := protojson.Marshal(resp)
+		if err != nil {
+			http.Error(w, "failed to encode response", http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		w.Write(body)
+		return
+	}
+
+	body, err := proto.Marshal(resp)
+	if err != nil {
+		http.Error(w, "failed to encode response", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/x-protobuf")
+	w.WriteHeader(http.StatusOK)
+	w.Write(body)
+}
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"io"
+	"net/http"
+
+	"github.com/artbyoscar/agenttrace/pkg/ingest"
+	"github.com/artbyoscar/agenttrace/pkg/ingest/translate"
+)
+
+// zipkinHandler accepts Zipkin v2 JSON span arrays, as emitted by any
+// standard Zipkin reporter.
+type zipkinHandler struct {
+	maxBodyBytes int64
+}
+
+func newZipkinHandler(maxBodyBytes int64) *zipkinHandler {
+	if maxBodyBytes <= 0 {
+		maxBodyBytes = defaultMaxBodyBytes
+	}
+	return &zipkinHandler{maxBodyBytes: maxBodyBytes}
+}
+
+func (h *zipkinHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if pipeline.Utilization() >= ingest.HighWatermark {
+		writeIngestError(w, ingest.ErrQueueFull)
+		return
+	}
+
+	body, err := io.ReadAll(io.LimitReader(r.Body, h.maxBodyBytes+1))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if int64(len(body)) > h.maxBodyBytes {
+		http.Error(w, errBodyTooLarge.Error(), http.StatusBadRequest)
+		return
+	}
+
+	spans, err := translate.FromZipkinV2JSON(body)
+	if err != nil {
+		http.Error(w, "malformed zipkin payload: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := ingestSpans(r.Context(), "zipkin", spans); err != nil {
+		writeIngestError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusAccepted)
+}
@@ -0,0 +1,152 @@
+package httpx
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// defaultAuthMaxBodyBytes bounds how much of a request body verifyHMAC
+// will buffer while checking a signature, ahead of any per-handler
+// limit. It matches the ingestion service's own default so an
+// HMAC-secured deployment gets the same body-size guarantee as an
+// unauthenticated one.
+const defaultAuthMaxBodyBytes = 16 << 20 // 16 MiB
+
+// AuthOptions configures the auth middleware. At least one of Tokens or
+// HMACSecret should be set; requests are accepted if they satisfy
+// either configured scheme.
+type AuthOptions struct {
+	// Tokens is the static set of accepted bearer tokens.
+	Tokens map[string]bool
+	// HMACSecret, when set, enables shared-secret request signing: the
+	// client sends X-Agenttrace-Signature as hex(HMAC-SHA256(body)).
+	HMACSecret []byte
+	// MaxBodyBytes bounds how much of the body verifyHMAC will buffer
+	// to check a signature. Defaults to defaultAuthMaxBodyBytes.
+	MaxBodyBytes int64
+}
+
+// AuthOptionsFromEnv builds AuthOptions from:
+//
+//	AGENTTRACE_AUTH_TOKENS        comma-separated bearer tokens
+//	AGENTTRACE_AUTH_HMAC_SECRET   shared secret for signed requests
+//	AGENTTRACE_MAX_BODY_BYTES     cap applied before signature verification
+//
+// Returns a zero-value Tokens/HMACSecret (auth disabled) if neither is
+// set.
+func AuthOptionsFromEnv() AuthOptions {
+	opts := AuthOptions{MaxBodyBytes: defaultAuthMaxBodyBytes}
+	if v := os.Getenv("AGENTTRACE_AUTH_TOKENS"); v != "" {
+		opts.Tokens = make(map[string]bool)
+		for _, t := range strings.Split(v, ",") {
+			if t = strings.TrimSpace(t); t != "" {
+				opts.Tokens[t] = true
+			}
+		}
+	}
+	if v := os.Getenv("AGENTTRACE_AUTH_HMAC_SECRET"); v != "" {
+		opts.HMACSecret = []byte(v)
+	}
+	if v := os.Getenv("AGENTTRACE_MAX_BODY_BYTES"); v != "" {
+		if n, err := strconv.ParseInt(v, 10, 64); err == nil && n > 0 {
+			opts.MaxBodyBytes = n
+		}
+	}
+	return opts
+}
+
+const signatureHeader = "X-Agenttrace-Signature"
+
+// Auth returns middleware enforcing AuthOptions. If neither a token
+// list nor an HMAC secret is configured, it passes all requests
+// through unchanged — auth is opt-in per deployment.
+func Auth(opts AuthOptions) Middleware {
+	maxBodyBytes := opts.MaxBodyBytes
+	if maxBodyBytes <= 0 {
+		maxBodyBytes = defaultAuthMaxBodyBytes
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if len(opts.Tokens) == 0 && len(opts.HMACSecret) == 0 {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			if len(opts.Tokens) > 0 && bearerTokenValid(r, opts.Tokens) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			if len(opts.HMACSecret) > 0 {
+				body, ok, err := verifyHMAC(w, r, opts.HMACSecret, maxBodyBytes)
+				if err != nil {
+					var maxBytesErr *http.MaxBytesError
+					if errors.As(err, &maxBytesErr) {
+						http.Error(w, "request body exceeds max body size", http.StatusRequestEntityTooLarge)
+						return
+					}
+					http.Error(w, "failed to read request body", http.StatusInternalServerError)
+					return
+				}
+				if ok {
+					r.Body = io.NopCloser(bytes.NewReader(body))
+					next.ServeHTTP(w, r)
+					return
+				}
+			}
+
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+		})
+	}
+}
+
+func bearerTokenValid(r *http.Request, tokens map[string]bool) bool {
+	const prefix = "Bearer "
+	header := r.Header.Get("Authorization")
+	if !strings.HasPrefix(header, prefix) {
+		return false
+	}
+	token := strings.TrimPrefix(header, prefix)
+	for t := range tokens {
+		if subtle.ConstantTimeCompare([]byte(t), []byte(token)) == 1 {
+			return true
+		}
+	}
+	return false
+}
+
+// verifyHMAC reads the full body (required to verify its signature),
+// capped at maxBodyBytes so a forged-but-well-formed signature header
+// can't be used to bypass the service's max body size, and returns the
+// body so the caller can restore it for downstream handlers.
+func verifyHMAC(w http.ResponseWriter, r *http.Request, secret []byte, maxBodyBytes int64) (body []byte, ok bool, err error) {
+	sig := r.Header.Get(signatureHeader)
+	if sig == "" {
+		return nil, false, nil
+	}
+	want, err := hex.DecodeString(sig)
+	if err != nil {
+		return nil, false, nil
+	}
+
+	body, err = io.ReadAll(http.MaxBytesReader(w, r.Body, maxBodyBytes))
+	if err != nil {
+		return nil, false, err
+	}
+
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(body)
+	got := mac.Sum(nil)
+
+	return body, hmac.Equal(got, want), nil
+}
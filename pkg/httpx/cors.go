@@ -0,0 +1,77 @@
+package httpx
+
+import (
+	"net/http"
+	"os"
+	"strings"
+)
+
+// CORSOptions configures the allowed cross-origin request surface.
+type CORSOptions struct {
+	AllowedOrigins []string
+	AllowedMethods []string
+	AllowedHeaders []string
+}
+
+// CORSOptionsFromEnv builds CORSOptions from comma-separated env vars,
+// falling back to sane defaults for a traces-ingestion API:
+//
+//	AGENTTRACE_CORS_ALLOWED_ORIGINS (default "*")
+//	AGENTTRACE_CORS_ALLOWED_METHODS (default "GET,POST,OPTIONS")
+//	AGENTTRACE_CORS_ALLOWED_HEADERS (default "Content-Type,Authorization")
+func CORSOptionsFromEnv() CORSOptions {
+	return CORSOptions{
+		AllowedOrigins: splitOrDefault(os.Getenv("AGENTTRACE_CORS_ALLOWED_ORIGINS"), []string{"*"}),
+		AllowedMethods: splitOrDefault(os.Getenv("AGENTTRACE_CORS_ALLOWED_METHODS"), []string{"GET", "POST", "OPTIONS"}),
+		AllowedHeaders: splitOrDefault(os.Getenv("AGENTTRACE_CORS_ALLOWED_HEADERS"), []string{"Content-Type", "Authorization"}),
+	}
+}
+
+func splitOrDefault(v string, def []string) []string {
+	if v == "" {
+		return def
+	}
+	parts := strings.Split(v, ",")
+	for i, p := range parts {
+		parts[i] = strings.TrimSpace(p)
+	}
+	return parts
+}
+
+// CORS returns middleware that applies the given CORS policy, answering
+// preflight OPTIONS requests directly so browser-based agents can POST
+// traces to agenttrace.
+func CORS(opts CORSOptions) Middleware {
+	allowedOrigins := make(map[string]bool, len(opts.AllowedOrigins))
+	allowAll := false
+	for _, o := range opts.AllowedOrigins {
+		if o == "*" {
+			allowAll = true
+		}
+		allowedOrigins[o] = true
+	}
+	methods := strings.Join(opts.AllowedMethods, ", ")
+	headers := strings.Join(opts.AllowedHeaders, ", ")
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			origin := r.Header.Get("Origin")
+			if origin != "" && (allowAll || allowedOrigins[origin]) {
+				if allowAll {
+					w.Header().Set("Access-Control-Allow-Origin", "*")
+				} else {
+					w.Header().Set("Access-Control-Allow-Origin", origin)
+					w.Header().Set("Vary", "Origin")
+				}
+				w.Header().Set("Access-Control-Allow-Methods", methods)
+				w.Header().Set("Access-Control-Allow-Headers", headers)
+			}
+
+			if r.Method == http.MethodOptions {
+				w.WriteHeader(http.StatusNoContent)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
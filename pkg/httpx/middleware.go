@@ -0,0 +1,47 @@
+// Package httpx holds the cross-cutting HTTP middleware shared by
+// agenttrace's services (ingestion, query, API): request logging, panic
+// recovery, CORS, and auth. Each service wires these onto its router
+// with router.Use(...) so the handlers themselves stay free of
+// cross-cutting concerns.
+package httpx
+
+import "net/http"
+
+// Middleware wraps an http.Handler with additional behavior. It's a
+// plain alias (not a defined type) so values returned by Recovery,
+// Logging, CORS, and Auth are directly assignable to router-specific
+// middleware types like gorilla/mux's MiddlewareFunc.
+type Middleware = func(http.Handler) http.Handler
+
+// Chain composes middleware so the first one listed runs outermost
+// (i.e. first on the way in, last on the way out).
+func Chain(mw ...Middleware) Middleware {
+	return func(next http.Handler) http.Handler {
+		for i := len(mw) - 1; i >= 0; i-- {
+			next = mw[i](next)
+		}
+		return next
+	}
+}
+
+// statusWriter wraps http.ResponseWriter to capture the status code and
+// byte count written, for logging and metrics.
+type statusWriter struct {
+	http.ResponseWriter
+	status int
+	bytes  int
+}
+
+func (w *statusWriter) WriteHeader(status int) {
+	w.status = status
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *statusWriter) Write(b []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	n, err := w.ResponseWriter.Write(b)
+	w.bytes += n
+	return n, err
+}
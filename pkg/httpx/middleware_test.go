@@ -0,0 +1,78 @@
+package httpx
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/rs/zerolog"
+)
+
+// TestLoggingRecoveryOrder is the regression test for the Recovery/
+// Logging ordering bug: Logging must be outermost so its "request
+// handled" line still fires when the handler panics, with Recovery
+// turning the panic into a 500 rather than the process crashing.
+func TestLoggingRecoveryOrder(t *testing.T) {
+	var buf bytes.Buffer
+	logger := zerolog.New(&buf)
+
+	panics := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic("boom")
+	})
+
+	handler := Logging(logger)(Recovery(logger)(panics))
+
+	req := httptest.NewRequest(http.MethodGet, "/v1/traces", nil)
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusInternalServerError {
+		t.Fatalf("status = %d, want 500", rec.Code)
+	}
+	if !strings.Contains(buf.String(), `"message":"request handled"`) {
+		t.Fatalf("expected a \"request handled\" log line even when the handler panicked, got: %s", buf.String())
+	}
+}
+
+func TestAuthBearerToken(t *testing.T) {
+	opts := AuthOptions{Tokens: map[string]bool{"secret": true}}
+	handler := Auth(opts)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	req := httptest.NewRequest(http.MethodPost, "/v1/traces", nil)
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	if rec.Code != http.StatusUnauthorized {
+		t.Fatalf("missing token: status = %d, want 401", rec.Code)
+	}
+
+	req = httptest.NewRequest(http.MethodPost, "/v1/traces", nil)
+	req.Header.Set("Authorization", "Bearer secret")
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("valid token: status = %d, want 200", rec.Code)
+	}
+}
+
+func TestCORSPreflight(t *testing.T) {
+	opts := CORSOptions{AllowedOrigins: []string{"https://agent.example"}, AllowedMethods: []string{"POST"}}
+	handler := CORS(opts)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Fatal("handler should not be called for an OPTIONS preflight")
+	}))
+
+	req := httptest.NewRequest(http.MethodOptions, "/v1/traces", nil)
+	req.Header.Set("Origin", "https://agent.example")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusNoContent {
+		t.Fatalf("status = %d, want 204", rec.Code)
+	}
+	if got := rec.Header().Get("Access-Control-Allow-Origin"); got != "https://agent.example" {
+		t.Errorf("Access-Control-Allow-Origin = %q", got)
+	}
+}
@@ -0,0 +1,46 @@
+package httpx
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+
+	"github.com/rs/zerolog"
+)
+
+// Recovery returns middleware that turns a panic in a downstream
+// handler into a 500 response carrying a trace id, instead of crashing
+// the process. The trace id is logged alongside the panic so it can be
+// correlated with an incident report.
+func Recovery(logger zerolog.Logger) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			defer func() {
+				if rec := recover(); rec != nil {
+					traceID := newTraceID()
+					logger.Error().
+						Str("trace_id", traceID).
+						Str("path", r.URL.Path).
+						Interface("panic", rec).
+						Msg("recovered from panic")
+
+					w.Header().Set("Content-Type", "application/json")
+					w.WriteHeader(http.StatusInternalServerError)
+					fmt.Fprintf(w, `{"error":"internal server error","trace_id":%q}`, traceID)
+				}
+			}()
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// newTraceID returns a random 16-byte hex id to correlate a recovered
+// panic across logs and the client-facing error response.
+func newTraceID() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "unknown"
+	}
+	return hex.EncodeToString(b)
+}
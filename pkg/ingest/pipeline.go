@@ -0,0 +1,141 @@
+// Package ingest hosts the async ingestion pipeline that sits between
+// the HTTP/gRPC receivers and the configured sinks: a bounded queue
+// feeding a worker pool, so a slow or down sink applies backpressure
+// instead of blocking (or OOM-ing) the receiver.
+package ingest
+
+import (
+	"context"
+	"errors"
+	"log"
+	"sync"
+	"sync/atomic"
+
+	"github.com/artbyoscar/agenttrace/pkg/sink"
+	"github.com/artbyoscar/agenttrace/pkg/trace"
+)
+
+// ErrQueueFull is returned by Submit when the queue has no room left;
+// callers (HTTP handlers) should translate this into a 429 response.
+var ErrQueueFull = errors.New("ingest: queue full")
+
+// ErrShuttingDown is returned by Submit once Shutdown has been called.
+var ErrShuttingDown = errors.New("ingest: pipeline is shutting down")
+
+// HighWatermark is the queue occupancy fraction at which callers should
+// start shedding load (e.g. respond 429) even before the queue is
+// completely full.
+const HighWatermark = 0.8
+
+// Pipeline is a bounded queue of span batches drained by a fixed pool
+// of workers, each of which writes every batch to every configured
+// sink.
+type Pipeline struct {
+	queue   chan []trace.Span
+	sinks   []sink.Sink
+	wg      sync.WaitGroup
+	dropped atomic.Int64
+
+	// shutdownMu serializes Submit against Shutdown so a Submit that
+	// observes draining == false can never lose the race with close(queue):
+	// Shutdown takes the write lock before closing, so no Submit is
+	// still inside its critical section (and about to send) once the
+	// channel closes.
+	shutdownMu sync.RWMutex
+	draining   bool
+}
+
+// NewPipeline creates a Pipeline with the given queue capacity (spans
+// batches, not individual spans) and worker count. The workers start
+// draining the queue before NewPipeline returns.
+func NewPipeline(sinks []sink.Sink, queueSize, workers int) *Pipeline {
+	if queueSize <= 0 {
+		queueSize = 1024
+	}
+	if workers <= 0 {
+		workers = 4
+	}
+	p := &Pipeline{
+		queue: make(chan []trace.Span, queueSize),
+		sinks: sinks,
+	}
+	p.wg.Add(workers)
+	for i := 0; i < workers; i++ {
+		go p.worker()
+	}
+	return p
+}
+
+func (p *Pipeline) worker() {
+	defer p.wg.Done()
+	for spans := range p.queue {
+		for _, s := range p.sinks {
+			if err := s.Write(context.Background(), spans); err != nil {
+				log.Printf("ingest: sink write failed: %v", err)
+			}
+		}
+	}
+}
+
+// Submit enqueues a batch of spans. It never blocks: if the queue is
+// full it drops the batch and returns ErrQueueFull.
+func (p *Pipeline) Submit(spans []trace.Span) error {
+	p.shutdownMu.RLock()
+	defer p.shutdownMu.RUnlock()
+
+	if p.draining {
+		return ErrShuttingDown
+	}
+	select {
+	case p.queue <- spans:
+		return nil
+	default:
+		p.dropped.Add(int64(len(spans)))
+		return ErrQueueFull
+	}
+}
+
+// Utilization returns the fraction of queue capacity currently in use,
+// for callers deciding whether to shed load ahead of HighWatermark.
+func (p *Pipeline) Utilization() float64 {
+	return float64(len(p.queue)) / float64(cap(p.queue))
+}
+
+// Dropped returns the cumulative number of spans dropped because the
+// queue was full.
+func (p *Pipeline) Dropped() int64 {
+	return p.dropped.Load()
+}
+
+// Shutdown stops accepting new batches, lets workers drain whatever is
+// already queued, and closes every sink. It returns early with the
+// context's error if draining doesn't finish before ctx is done.
+func (p *Pipeline) Shutdown(ctx context.Context) error {
+	p.shutdownMu.Lock()
+	p.draining = true
+	close(p.queue)
+	p.shutdownMu.Unlock()
+
+	drained := make(chan struct{})
+	go func() {
+		p.wg.Wait()
+		close(drained)
+	}()
+
+	select {
+	case <-drained:
+	case <-ctx.Done():
+		for _, s := range p.sinks {
+			s.Close()
+		}
+		return ctx.Err()
+	}
+
+	var firstErr error
+	for _, s := range p.sinks {
+		if err := s.Close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
@@ -0,0 +1,37 @@
+package ingest
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/artbyoscar/agenttrace/pkg/sink"
+	"github.com/artbyoscar/agenttrace/pkg/trace"
+)
+
+type nopSink struct{}
+
+func (nopSink) Write(context.Context, []trace.Span) error { return nil }
+func (nopSink) Close() error                              { return nil }
+
+// TestSubmitDuringShutdown exercises the race the maintainer flagged:
+// concurrent Submit calls racing Shutdown's close(queue) must never
+// panic with "send on closed channel", regardless of which wins. Run
+// with -race to catch the unsynchronized-close variant of this bug.
+func TestSubmitDuringShutdown(t *testing.T) {
+	p := NewPipeline([]sink.Sink{nopSink{}}, 4, 2)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_ = p.Submit([]trace.Span{{TraceID: "x"}})
+		}()
+	}
+
+	if err := p.Shutdown(context.Background()); err != nil {
+		t.Fatalf("Shutdown: %v", err)
+	}
+	wg.Wait()
+}
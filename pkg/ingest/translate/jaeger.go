@@ -0,0 +1,116 @@
+package translate
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/apache/thrift/lib/go/thrift"
+	jaegerthrift "github.com/jaegertracing/jaeger-idl/thrift-gen/jaeger"
+
+	"github.com/artbyoscar/agenttrace/pkg/trace"
+)
+
+// FromJaegerThrift decodes a Thrift-over-HTTP batch (TBinaryProtocol,
+// as emitted by jaeger-agent and jaeger-client-go's HTTP transport) into
+// agenttrace's internal span model.
+func FromJaegerThrift(data []byte) ([]trace.Span, error) {
+	buf := thrift.NewTMemoryBufferLen(len(data))
+	if _, err := buf.Write(data); err != nil {
+		return nil, fmt.Errorf("translate: buffer jaeger thrift payload: %w", err)
+	}
+	proto := thrift.NewTBinaryProtocolConf(buf, nil)
+
+	batch := &jaegerthrift.Batch{}
+	if err := batch.Read(context.Background(), proto); err != nil {
+		return nil, fmt.Errorf("translate: decode jaeger thrift batch: %w", err)
+	}
+	return FromJaegerBatch(batch), nil
+}
+
+// FromJaegerBatch converts an already-decoded Jaeger Thrift batch.
+func FromJaegerBatch(batch *jaegerthrift.Batch) []trace.Span {
+	var resource trace.Resource
+	if batch.Process != nil {
+		resource.Attributes = append(resource.Attributes, trace.KeyValue{
+			Key: "service.name", VType: trace.ValueString, Str: batch.Process.ServiceName,
+		})
+		resource.Attributes = append(resource.Attributes, convertJaegerTags(batch.Process.Tags)...)
+	}
+
+	spans := make([]trace.Span, 0, len(batch.Spans))
+	for _, s := range batch.Spans {
+		spans = append(spans, convertJaegerSpan(s, resource))
+	}
+	return spans
+}
+
+func convertJaegerSpan(s *jaegerthrift.Span, resource trace.Resource) trace.Span {
+	span := trace.Span{
+		TraceID:       jaegerTraceIDHex(s.TraceIdHigh, s.TraceIdLow),
+		SpanID:        jaegerIDHex(s.SpanId),
+		Name:          s.OperationName,
+		Kind:          "internal",
+		StartUnixNano: uint64(s.StartTime) * 1000,
+		EndUnixNano:   uint64(s.StartTime+s.Duration) * 1000,
+		Attributes:    convertJaegerTags(s.Tags),
+		Resource:      resource,
+	}
+	for _, ref := range s.References {
+		if ref.RefType == jaegerthrift.SpanRefType_CHILD_OF && span.ParentSpanID == "" {
+			span.ParentSpanID = jaegerIDHex(ref.SpanId)
+			continue
+		}
+		span.Links = append(span.Links, trace.Link{
+			TraceID: jaegerTraceIDHex(ref.TraceIdHigh, ref.TraceIdLow),
+			SpanID:  jaegerIDHex(ref.SpanId),
+		})
+	}
+	for _, l := range s.Logs {
+		span.Events = append(span.Events, trace.Event{
+			TimeUnixNano: uint64(l.Timestamp) * 1000,
+			Attributes:   convertJaegerTags(l.Fields),
+		})
+	}
+	return span
+}
+
+func convertJaegerTags(tags []*jaegerthrift.Tag) []trace.KeyValue {
+	out := make([]trace.KeyValue, 0, len(tags))
+	for _, t := range tags {
+		kv := trace.KeyValue{Key: t.Key}
+		switch t.VType {
+		case jaegerthrift.TagType_LONG:
+			kv.VType = trace.ValueInt
+			if t.VLong != nil {
+				kv.Int = *t.VLong
+			}
+		case jaegerthrift.TagType_DOUBLE:
+			kv.VType = trace.ValueFloat
+			if t.VDouble != nil {
+				kv.Float = *t.VDouble
+			}
+		case jaegerthrift.TagType_BOOL:
+			kv.VType = trace.ValueBool
+			if t.VBool != nil {
+				kv.Bool = *t.VBool
+			}
+		default:
+			kv.VType = trace.ValueString
+			if t.VStr != nil {
+				kv.Str = *t.VStr
+			}
+		}
+		out = append(out, kv)
+	}
+	return out
+}
+
+// jaegerTraceIDHex renders Jaeger's split 64-bit high/low trace ID as
+// the 32 hex character form used by the internal model.
+func jaegerTraceIDHex(high, low int64) string {
+	return fmt.Sprintf("%016x%016x", uint64(high), uint64(low))
+}
+
+func jaegerIDHex(id int64) string {
+	return fmt.Sprintf("%016x", uint64(id))
+}
@@ -0,0 +1,105 @@
+package translate
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/apache/thrift/lib/go/thrift"
+	jaegerthrift "github.com/jaegertracing/jaeger-idl/thrift-gen/jaeger"
+)
+
+func TestFromZipkinV2JSON_Golden(t *testing.T) {
+	data, err := os.ReadFile("testdata/zipkin_golden.json")
+	if err != nil {
+		t.Fatalf("read golden payload: %v", err)
+	}
+
+	spans, err := FromZipkinV2JSON(data)
+	if err != nil {
+		t.Fatalf("FromZipkinV2JSON: %v", err)
+	}
+	if len(spans) != 1 {
+		t.Fatalf("got %d spans, want 1", len(spans))
+	}
+
+	span := spans[0]
+	if span.TraceID != "4bf92f3577b34da6a3ce929d0e0e4736" {
+		t.Errorf("TraceID = %q", span.TraceID)
+	}
+	if span.SpanID != "00f067aa0ba902b7" {
+		t.Errorf("SpanID = %q", span.SpanID)
+	}
+	if span.ParentSpanID != "0020000000000001" {
+		t.Errorf("ParentSpanID = %q", span.ParentSpanID)
+	}
+	if span.Kind != "server" {
+		t.Errorf("Kind = %q, want server", span.Kind)
+	}
+	if span.StartUnixNano != 1700000000000000000 {
+		t.Errorf("StartUnixNano = %d", span.StartUnixNano)
+	}
+	if span.EndUnixNano != 1700000000015000000 {
+		t.Errorf("EndUnixNano = %d", span.EndUnixNano)
+	}
+	if len(span.Events) != 1 || span.Events[0].Name != "cache-miss" {
+		t.Errorf("Events = %+v", span.Events)
+	}
+}
+
+// goldenJaegerBatch is the fixture batch shared by the encode and decode
+// halves of the round-trip test below: it stands in for a payload
+// captured from jaeger-client-go's HTTP reporter.
+func goldenJaegerBatch() *jaegerthrift.Batch {
+	vStr := "GET"
+	return &jaegerthrift.Batch{
+		Process: &jaegerthrift.Process{
+			ServiceName: "checkout-service",
+		},
+		Spans: []*jaegerthrift.Span{
+			{
+				TraceIdHigh:   0x4bf92f3577b34da6,
+				TraceIdLow:    0x3ce929d0e0e4736,
+				SpanId:        0x00f067aa0ba902b7,
+				OperationName: "POST /checkout",
+				StartTime:     1700000000000000,
+				Duration:      15000,
+				Tags: []*jaegerthrift.Tag{
+					{Key: "http.method", VType: jaegerthrift.TagType_STRING, VStr: &vStr},
+				},
+			},
+		},
+	}
+}
+
+func TestFromJaegerThrift_RoundTrip(t *testing.T) {
+	batch := goldenJaegerBatch()
+
+	buf := thrift.NewTMemoryBuffer()
+	proto := thrift.NewTBinaryProtocolConf(buf, nil)
+	if err := batch.Write(context.Background(), proto); err != nil {
+		t.Fatalf("encode golden batch: %v", err)
+	}
+
+	spans, err := FromJaegerThrift(buf.Bytes())
+	if err != nil {
+		t.Fatalf("FromJaegerThrift: %v", err)
+	}
+	if len(spans) != 1 {
+		t.Fatalf("got %d spans, want 1", len(spans))
+	}
+
+	span := spans[0]
+	if span.TraceID != "4bf92f3577b34da603ce929d0e0e4736" {
+		t.Errorf("TraceID = %q", span.TraceID)
+	}
+	if span.SpanID != "00f067aa0ba902b7" {
+		t.Errorf("SpanID = %q", span.SpanID)
+	}
+	if span.Name != "POST /checkout" {
+		t.Errorf("Name = %q", span.Name)
+	}
+	if len(span.Attributes) != 1 || span.Attributes[0].Str != "GET" {
+		t.Errorf("Attributes = %+v", span.Attributes)
+	}
+}
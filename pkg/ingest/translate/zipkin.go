@@ -0,0 +1,117 @@
+// Package translate converts non-OTLP wire formats (Jaeger Thrift,
+// Zipkin v2 JSON) into agenttrace's internal trace.Span model, so the
+// ingestion service can treat every format the same way past the
+// decode step.
+package translate
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/artbyoscar/agenttrace/pkg/trace"
+)
+
+// zipkinSpan mirrors the Zipkin v2 JSON span schema:
+// https://zipkin.io/zipkin-api/#/default/post_spans
+type zipkinSpan struct {
+	TraceID       string             `json:"traceId"`
+	ID            string             `json:"id"`
+	ParentID      string             `json:"parentId,omitempty"`
+	Name          string             `json:"name"`
+	Kind          string             `json:"kind,omitempty"`
+	Timestamp     uint64             `json:"timestamp"` // microseconds since epoch
+	Duration      uint64             `json:"duration"`  // microseconds
+	LocalEndpoint *zipkinEndpoint    `json:"localEndpoint,omitempty"`
+	Tags          map[string]string  `json:"tags,omitempty"`
+	Annotations   []zipkinAnnotation `json:"annotations,omitempty"`
+}
+
+type zipkinEndpoint struct {
+	ServiceName string `json:"serviceName,omitempty"`
+}
+
+type zipkinAnnotation struct {
+	Timestamp uint64 `json:"timestamp"`
+	Value     string `json:"value"`
+}
+
+// FromZipkinV2JSON decodes a Zipkin v2 JSON span array into agenttrace's
+// internal span model.
+func FromZipkinV2JSON(data []byte) ([]trace.Span, error) {
+	var zspans []zipkinSpan
+	if err := json.Unmarshal(data, &zspans); err != nil {
+		return nil, fmt.Errorf("translate: decode zipkin v2 json: %w", err)
+	}
+
+	spans := make([]trace.Span, 0, len(zspans))
+	for _, z := range zspans {
+		span, err := convertZipkinSpan(z)
+		if err != nil {
+			return nil, err
+		}
+		spans = append(spans, span)
+	}
+	return spans, nil
+}
+
+func convertZipkinSpan(z zipkinSpan) (trace.Span, error) {
+	traceID, err := padHex(z.TraceID, 32)
+	if err != nil {
+		return trace.Span{}, fmt.Errorf("translate: zipkin traceId: %w", err)
+	}
+	spanID, err := padHex(z.ID, 16)
+	if err != nil {
+		return trace.Span{}, fmt.Errorf("translate: zipkin id: %w", err)
+	}
+	var parentID string
+	if z.ParentID != "" {
+		parentID, err = padHex(z.ParentID, 16)
+		if err != nil {
+			return trace.Span{}, fmt.Errorf("translate: zipkin parentId: %w", err)
+		}
+	}
+
+	span := trace.Span{
+		TraceID:       traceID,
+		SpanID:        spanID,
+		ParentSpanID:  parentID,
+		Name:          z.Name,
+		Kind:          strings.ToLower(z.Kind),
+		StartUnixNano: z.Timestamp * 1000,
+		EndUnixNano:   (z.Timestamp + z.Duration) * 1000,
+	}
+	if span.Kind == "" {
+		span.Kind = "internal"
+	}
+	if z.LocalEndpoint != nil && z.LocalEndpoint.ServiceName != "" {
+		span.Resource.Attributes = append(span.Resource.Attributes, trace.KeyValue{
+			Key: "service.name", VType: trace.ValueString, Str: z.LocalEndpoint.ServiceName,
+		})
+	}
+	for k, v := range z.Tags {
+		span.Attributes = append(span.Attributes, trace.KeyValue{Key: k, VType: trace.ValueString, Str: v})
+	}
+	for _, a := range z.Annotations {
+		span.Events = append(span.Events, trace.Event{
+			Name:         a.Value,
+			TimeUnixNano: a.Timestamp * 1000,
+		})
+	}
+	return span, nil
+}
+
+// padHex left-pads a Zipkin 64-bit (16 hex char) trace/span ID out to
+// the expected width; Zipkin allows 64-bit trace IDs that OTLP's 128-bit
+// IDs must accommodate.
+func padHex(id string, width int) (string, error) {
+	if len(id) > width {
+		return "", fmt.Errorf("id %q longer than %d hex chars", id, width)
+	}
+	for _, c := range id {
+		if !strings.ContainsRune("0123456789abcdefABCDEF", c) {
+			return "", fmt.Errorf("id %q is not hex", id)
+		}
+	}
+	return strings.Repeat("0", width-len(id)) + strings.ToLower(id), nil
+}
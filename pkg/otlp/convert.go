@@ -0,0 +1,132 @@
+// Package otlp converts OpenTelemetry protocol buffers into agenttrace's
+// internal trace.Span model.
+package otlp
+
+import (
+	"encoding/hex"
+
+	commonpb "go.opentelemetry.io/proto/otlp/common/v1"
+	resourcepb "go.opentelemetry.io/proto/otlp/resource/v1"
+	tracepb "go.opentelemetry.io/proto/otlp/trace/v1"
+
+	"github.com/artbyoscar/agenttrace/pkg/trace"
+)
+
+// ToSpans flattens a slice of ResourceSpans (the top-level message of
+// ExportTraceServiceRequest) into agenttrace's internal span model.
+//
+// Malformed spans (bad trace/span IDs) are skipped rather than rejecting
+// the whole batch; the caller is expected to report how many were
+// dropped via partial_success.
+func ToSpans(resourceSpans []*tracepb.ResourceSpans) (spans []trace.Span, rejected int64) {
+	for _, rs := range resourceSpans {
+		resource := convertResource(rs.GetResource())
+		for _, ss := range rs.GetScopeSpans() {
+			scope := convertScope(ss.GetScope())
+			for _, s := range ss.GetSpans() {
+				span, ok := convertSpan(s, resource, scope)
+				if !ok {
+					rejected++
+					continue
+				}
+				spans = append(spans, span)
+			}
+		}
+	}
+	return spans, rejected
+}
+
+func convertSpan(s *tracepb.Span, resource trace.Resource, scope trace.Scope) (trace.Span, bool) {
+	traceID := hex.EncodeToString(s.GetTraceId())
+	spanID := hex.EncodeToString(s.GetSpanId())
+	if len(traceID) != 32 || len(spanID) != 16 {
+		return trace.Span{}, false
+	}
+
+	span := trace.Span{
+		TraceID:       traceID,
+		SpanID:        spanID,
+		ParentSpanID:  hex.EncodeToString(s.GetParentSpanId()),
+		Name:          s.GetName(),
+		Kind:          convertKind(s.GetKind()),
+		StartUnixNano: s.GetStartTimeUnixNano(),
+		EndUnixNano:   s.GetEndTimeUnixNano(),
+		Attributes:    convertAttributes(s.GetAttributes()),
+		Status:        convertStatus(s.GetStatus()),
+		Resource:      resource,
+		Scope:         scope,
+	}
+	for _, e := range s.GetEvents() {
+		span.Events = append(span.Events, trace.Event{
+			Name:         e.GetName(),
+			TimeUnixNano: e.GetTimeUnixNano(),
+			Attributes:   convertAttributes(e.GetAttributes()),
+		})
+	}
+	for _, l := range s.GetLinks() {
+		span.Links = append(span.Links, trace.Link{
+			TraceID:    hex.EncodeToString(l.GetTraceId()),
+			SpanID:     hex.EncodeToString(l.GetSpanId()),
+			Attributes: convertAttributes(l.GetAttributes()),
+		})
+	}
+	return span, true
+}
+
+func convertKind(k tracepb.Span_SpanKind) string {
+	switch k {
+	case tracepb.Span_SPAN_KIND_SERVER:
+		return "server"
+	case tracepb.Span_SPAN_KIND_CLIENT:
+		return "client"
+	case tracepb.Span_SPAN_KIND_PRODUCER:
+		return "producer"
+	case tracepb.Span_SPAN_KIND_CONSUMER:
+		return "consumer"
+	default:
+		return "internal"
+	}
+}
+
+func convertStatus(s *tracepb.Status) trace.Status {
+	status := trace.Status{Message: s.GetMessage()}
+	switch s.GetCode() {
+	case tracepb.Status_STATUS_CODE_OK:
+		status.Code = trace.StatusOK
+	case tracepb.Status_STATUS_CODE_ERROR:
+		status.Code = trace.StatusError
+	default:
+		status.Code = trace.StatusUnset
+	}
+	return status
+}
+
+func convertResource(r *resourcepb.Resource) trace.Resource {
+	return trace.Resource{Attributes: convertAttributes(r.GetAttributes())}
+}
+
+func convertScope(s *commonpb.InstrumentationScope) trace.Scope {
+	return trace.Scope{Name: s.GetName(), Version: s.GetVersion()}
+}
+
+func convertAttributes(kvs []*commonpb.KeyValue) []trace.KeyValue {
+	out := make([]trace.KeyValue, 0, len(kvs))
+	for _, kv := range kvs {
+		out = append(out, convertAttribute(kv))
+	}
+	return out
+}
+
+func convertAttribute(kv *commonpb.KeyValue) trace.KeyValue {
+	v := kv.GetValue()
+	switch val := v.GetValue().(type) {
+	case *commonpb.AnyValue_IntValue:
+		return trace.KeyValue{Key: kv.GetKey(), VType: trace.ValueInt, Int: val.IntValue}
+	case *commonpb.AnyValue_DoubleValue:
+		return trace.KeyValue{Key: kv.GetKey(), VType: trace.ValueFloat, Float: val.DoubleValue}
+	case *commonpb.AnyValue_BoolValue:
+		return trace.KeyValue{Key: kv.GetKey(), VType: trace.ValueBool, Bool: val.BoolValue}
+	default:
+		return trace.KeyValue{Key: kv.GetKey(), VType: trace.ValueString, Str: v.GetStringValue()}
+	}
+}
@@ -0,0 +1,54 @@
+package sink
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	kafka "github.com/segmentio/kafka-go"
+
+	"github.com/artbyoscar/agenttrace/pkg/trace"
+)
+
+// KafkaSink publishes each span as a JSON-encoded message, e.g.
+// kafka://broker1:9092,broker2:9092/traces.
+type KafkaSink struct {
+	writer *kafka.Writer
+}
+
+// NewKafkaSink parses a kafka://broker[,broker...]/topic URL-ish
+// address and opens a writer against it.
+func NewKafkaSink(addr string) (*KafkaSink, error) {
+	rest := strings.TrimPrefix(addr, "kafka://")
+	hostsAndTopic := strings.SplitN(rest, "/", 2)
+	if len(hostsAndTopic) != 2 || hostsAndTopic[1] == "" {
+		return nil, fmt.Errorf("sink: invalid kafka address %q, want kafka://broker[,broker]/topic", addr)
+	}
+	brokers := strings.Split(hostsAndTopic[0], ",")
+	topic := hostsAndTopic[1]
+
+	return &KafkaSink{
+		writer: &kafka.Writer{
+			Addr:     kafka.TCP(brokers...),
+			Topic:    topic,
+			Balancer: &kafka.LeastBytes{},
+		},
+	}, nil
+}
+
+func (s *KafkaSink) Write(ctx context.Context, spans []trace.Span) error {
+	msgs := make([]kafka.Message, 0, len(spans))
+	for _, span := range spans {
+		value, err := json.Marshal(span)
+		if err != nil {
+			return fmt.Errorf("sink: marshal span for kafka: %w", err)
+		}
+		msgs = append(msgs, kafka.Message{Key: []byte(span.TraceID), Value: value})
+	}
+	return s.writer.WriteMessages(ctx, msgs...)
+}
+
+func (s *KafkaSink) Close() error {
+	return s.writer.Close()
+}
@@ -0,0 +1,53 @@
+package sink
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/nats-io/nats.go"
+
+	"github.com/artbyoscar/agenttrace/pkg/trace"
+)
+
+// NATSSink publishes each span as a JSON-encoded message to a subject,
+// e.g. nats://localhost:4222/traces.
+type NATSSink struct {
+	conn    *nats.Conn
+	subject string
+}
+
+// NewNATSSink parses a nats://host:port/subject address and connects.
+func NewNATSSink(addr string) (*NATSSink, error) {
+	rest := strings.TrimPrefix(addr, "nats://")
+	hostAndSubject := strings.SplitN(rest, "/", 2)
+	if len(hostAndSubject) != 2 || hostAndSubject[1] == "" {
+		return nil, fmt.Errorf("sink: invalid nats address %q, want nats://host:port/subject", addr)
+	}
+
+	conn, err := nats.Connect("nats://" + hostAndSubject[0])
+	if err != nil {
+		return nil, fmt.Errorf("sink: connect to nats at %q: %w", hostAndSubject[0], err)
+	}
+
+	return &NATSSink{conn: conn, subject: hostAndSubject[1]}, nil
+}
+
+func (s *NATSSink) Write(_ context.Context, spans []trace.Span) error {
+	for _, span := range spans {
+		value, err := json.Marshal(span)
+		if err != nil {
+			return fmt.Errorf("sink: marshal span for nats: %w", err)
+		}
+		if err := s.conn.Publish(s.subject, value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *NATSSink) Close() error {
+	s.conn.Close()
+	return nil
+}
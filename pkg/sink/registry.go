@@ -0,0 +1,72 @@
+package sink
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// ParseSinks builds one Sink per comma-separated entry in spec, e.g.
+//
+//	AGENTTRACE_SINKS=kafka://broker:9092/traces,file:///var/log/traces.ndjson
+//
+// An empty spec returns (nil, nil); callers should fall back to a
+// StdoutSink in that case so ingestion always has somewhere to go.
+func ParseSinks(spec string) ([]Sink, error) {
+	if strings.TrimSpace(spec) == "" {
+		return nil, nil
+	}
+
+	// A piece with no scheme continues the entry before it, so the extra
+	// brokers of kafka://b1:9092,b2:9092/traces stay in one address.
+	var entries []string
+	for _, raw := range strings.Split(spec, ",") {
+		raw = strings.TrimSpace(raw)
+		if raw == "" {
+			continue
+		}
+		if !strings.Contains(raw, "://") && raw != "stdout" && len(entries) > 0 {
+			entries[len(entries)-1] += "," + raw
+			continue
+		}
+		entries = append(entries, raw)
+	}
+
+	var sinks []Sink
+	for _, raw := range entries {
+		s, err := parseSink(raw)
+		if err != nil {
+			return nil, fmt.Errorf("sink: %q: %w", raw, err)
+		}
+		sinks = append(sinks, s)
+	}
+	return sinks, nil
+}
+
+// SinksFromEnv is a convenience wrapper around ParseSinks reading
+// AGENTTRACE_SINKS, defaulting to a single StdoutSink when unset.
+func SinksFromEnv() ([]Sink, error) {
+	sinks, err := ParseSinks(os.Getenv("AGENTTRACE_SINKS"))
+	if err != nil {
+		return nil, err
+	}
+	if len(sinks) == 0 {
+		sinks = []Sink{NewStdoutSink(os.Stdout)}
+	}
+	return sinks, nil
+}
+
+func parseSink(raw string) (Sink, error) {
+	switch {
+	case raw == "stdout://" || raw == "stdout":
+		return NewStdoutSink(os.Stdout), nil
+	case strings.HasPrefix(raw, "file://"):
+		return NewFileSink(strings.TrimPrefix(raw, "file://"))
+	case strings.HasPrefix(raw, "kafka://"):
+		return NewKafkaSink(raw)
+	case strings.HasPrefix(raw, "nats://"):
+		return NewNATSSink(raw)
+	default:
+		return nil, fmt.Errorf("unknown sink scheme, want one of kafka/nats/file/stdout")
+	}
+}
@@ -0,0 +1,18 @@
+// Package sink defines the pluggable destinations the ingestion
+// pipeline fans spans out to (Kafka, NATS, a file, or stdout), and a
+// registry that builds them from AGENTTRACE_SINKS.
+package sink
+
+import (
+	"context"
+
+	"github.com/artbyoscar/agenttrace/pkg/trace"
+)
+
+// Sink accepts batches of normalized spans. Implementations must be
+// safe for concurrent use: the pipeline's worker pool calls Write from
+// multiple goroutines.
+type Sink interface {
+	Write(ctx context.Context, spans []trace.Span) error
+	Close() error
+}
@@ -0,0 +1,37 @@
+package sink
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"sync"
+
+	"github.com/artbyoscar/agenttrace/pkg/trace"
+)
+
+// StdoutSink writes each span as one NDJSON line to the given writer.
+// It's the default sink for local development.
+type StdoutSink struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+// NewStdoutSink returns a Sink writing NDJSON to w.
+func NewStdoutSink(w io.Writer) *StdoutSink {
+	return &StdoutSink{w: w}
+}
+
+func (s *StdoutSink) Write(_ context.Context, spans []trace.Span) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	enc := json.NewEncoder(s.w)
+	for _, span := range spans {
+		if err := enc.Encode(span); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *StdoutSink) Close() error { return nil }
@@ -0,0 +1,88 @@
+// Package trace defines agenttrace's internal span representation.
+//
+// Every ingestion format (OTLP, Jaeger, Zipkin, ...) is normalized into
+// this model before it is queued, stored, or queried, so the rest of the
+// module never needs to know where a span came from. JSON tags are
+// provided so sinks can serialize spans (e.g. to NDJSON) without a
+// separate wire type.
+package trace
+
+// StatusCode mirrors the three-value status model shared by OTLP, Jaeger
+// and Zipkin spans.
+type StatusCode int
+
+const (
+	StatusUnset StatusCode = iota
+	StatusOK
+	StatusError
+)
+
+// KeyValue is a single attribute. Value holds exactly one of the typed
+// fields below; VType indicates which one is populated.
+type KeyValue struct {
+	Key   string    `json:"key"`
+	VType ValueType `json:"type"`
+	Str   string    `json:"str,omitempty"`
+	Int   int64     `json:"int,omitempty"`
+	Float float64   `json:"float,omitempty"`
+	Bool  bool      `json:"bool,omitempty"`
+}
+
+type ValueType int
+
+const (
+	ValueString ValueType = iota
+	ValueInt
+	ValueFloat
+	ValueBool
+)
+
+// Status carries the outcome of the operation the span represents.
+type Status struct {
+	Code    StatusCode `json:"code"`
+	Message string     `json:"message,omitempty"`
+}
+
+// Event is a timestamped annotation attached to a span.
+type Event struct {
+	Name         string     `json:"name"`
+	TimeUnixNano uint64     `json:"time_unix_nano"`
+	Attributes   []KeyValue `json:"attributes,omitempty"`
+}
+
+// Link points to a causally related span, possibly in another trace.
+type Link struct {
+	TraceID    string     `json:"trace_id"` // 32 hex chars
+	SpanID     string     `json:"span_id"`  // 16 hex chars
+	Attributes []KeyValue `json:"attributes,omitempty"`
+}
+
+// Resource describes the entity (service, host, ...) that produced a
+// batch of spans.
+type Resource struct {
+	Attributes []KeyValue `json:"attributes,omitempty"`
+}
+
+// Scope identifies the instrumentation library that recorded a span.
+type Scope struct {
+	Name    string `json:"name,omitempty"`
+	Version string `json:"version,omitempty"`
+}
+
+// Span is agenttrace's normalized representation of a single span,
+// regardless of the wire format it arrived in.
+type Span struct {
+	TraceID       string     `json:"trace_id"`                 // 32 hex chars
+	SpanID        string     `json:"span_id"`                  // 16 hex chars
+	ParentSpanID  string     `json:"parent_span_id,omitempty"` // 16 hex chars, empty for root spans
+	Name          string     `json:"name"`
+	Kind          string     `json:"kind"` // "internal", "server", "client", "producer", "consumer"
+	StartUnixNano uint64     `json:"start_unix_nano"`
+	EndUnixNano   uint64     `json:"end_unix_nano"`
+	Attributes    []KeyValue `json:"attributes,omitempty"`
+	Events        []Event    `json:"events,omitempty"`
+	Links         []Link     `json:"links,omitempty"`
+	Status        Status     `json:"status"`
+	Resource      Resource   `json:"resource"`
+	Scope         Scope      `json:"scope"`
+}